@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/alaypatel07/extractor/pkg/export"
+	"github.com/alaypatel07/extractor/pkg/prune"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// defaultMaxInflight bounds how many List calls can be outstanding against
+// the API server at once when --max-inflight is not set.
+const defaultMaxInflight = 10
+
+// ExtractorOptions holds the flags for "extract all".
+type ExtractorOptions struct {
+	// MaxInflight caps the number of concurrent getObjects requests Run
+	// issues against the API server.
+	MaxInflight int
+
+	// ClusterScope, when set, also considers cluster-scoped resources
+	// instead of only namespaced ones.
+	ClusterScope bool
+	// Include, when non-empty, whitelists resources by plural name or
+	// short name (e.g. "pods" or "po"); anything not listed is skipped.
+	Include []string
+	// Exclude skips resources by plural name or short name, even if they
+	// would otherwise be included.
+	Exclude []string
+	// Selector is a label selector passed through to the List call.
+	Selector string
+	// FieldSelector is a field selector passed through to the List call.
+	FieldSelector string
+
+	// OutputDir, when non-empty, makes the run write every discovered
+	// object to disk under this directory instead of only printing a
+	// summary.
+	OutputDir string
+	// Format is the manifest format written under OutputDir: "yaml",
+	// "json", or "single-file".
+	Format string
+	// StripStatus removes the status subresource from exported objects.
+	StripStatus bool
+	// Kustomize writes a kustomization.yaml alongside the exported
+	// manifests.
+	Kustomize bool
+	// KeepOwned keeps objects whose owner is also captured in the export,
+	// instead of pruning them as apply-time churn.
+	KeepOwned bool
+}
+
+// NewExtractorOptions provides an instance of ExtractorOptions with default values
+func NewExtractorOptions() *ExtractorOptions {
+	return &ExtractorOptions{
+		MaxInflight: defaultMaxInflight,
+	}
+}
+
+// AddFlags binds the "extract all" flags to flags.
+func (e *ExtractorOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&e.MaxInflight, "max-inflight", e.MaxInflight, "maximum number of in-flight list requests to the API server")
+	flags.BoolVar(&e.ClusterScope, "cluster-scope", e.ClusterScope, "also consider cluster-scoped resources, not just namespaced ones")
+	flags.StringArrayVar(&e.Include, "include", e.Include, "resource (name or short name) to include; may be repeated. If set, only these resources are considered")
+	flags.StringArrayVar(&e.Exclude, "exclude", e.Exclude, "resource (name or short name) to exclude; may be repeated")
+	flags.StringVarP(&e.Selector, "selector", "l", e.Selector, "label selector to filter listed objects")
+	flags.StringVar(&e.FieldSelector, "field-selector", e.FieldSelector, "field selector to filter listed objects")
+	flags.StringVar(&e.OutputDir, "output-dir", e.OutputDir, "directory to export discovered objects into; if empty, only a summary is printed")
+	flags.StringVar(&e.Format, "format", e.Format, "manifest format to export: yaml, json, or single-file")
+	flags.BoolVar(&e.StripStatus, "strip-status", e.StripStatus, "strip the status subresource from exported objects")
+	flags.BoolVar(&e.Kustomize, "kustomize", e.Kustomize, "write a kustomization.yaml listing every exported file")
+	flags.BoolVar(&e.KeepOwned, "keep-owned", e.KeepOwned, "keep objects whose owner is also captured, instead of pruning them")
+}
+
+// groupResource contains the APIGroup and APIResource
+type groupResource struct {
+	APIGroup        string
+	APIVersion      string
+	APIGroupVersion string
+	APIResource     metav1.APIResource
+}
+
+type sortableResource struct {
+	resources []groupResource
+	sortBy    string
+}
+
+func (s sortableResource) Len() int { return len(s.resources) }
+func (s sortableResource) Swap(i, j int) {
+	s.resources[i], s.resources[j] = s.resources[j], s.resources[i]
+}
+func (s sortableResource) Less(i, j int) bool {
+	ret := strings.Compare(s.compareValues(i, j))
+	if ret > 0 {
+		return false
+	} else if ret == 0 {
+		return strings.Compare(s.resources[i].APIResource.Name, s.resources[j].APIResource.Name) < 0
+	}
+	return true
+}
+
+func (s sortableResource) compareValues(i, j int) (string, string) {
+	switch s.sortBy {
+	case "name":
+		return s.resources[i].APIResource.Name, s.resources[j].APIResource.Name
+	case "kind":
+		return s.resources[i].APIResource.Kind, s.resources[j].APIResource.Kind
+	}
+	return s.resources[i].APIGroup, s.resources[j].APIGroup
+}
+
+// newAllCommand builds "extract all", which discovers every preferred
+// resource in the current context's namespace and optionally exports it.
+func newAllCommand(streams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, discoveryCacheTTL *time.Duration) *cobra.Command {
+	o := NewExtractorOptions()
+
+	cmd := &cobra.Command{
+		Use:   "all",
+		Short: "Discover every preferred resource in the current namespace, and optionally export it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAll(streams, configFlags, *discoveryCacheTTL, o)
+		},
+	}
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func runAll(streams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, discoveryCacheTTL time.Duration, e *ExtractorOptions) error {
+	namespace, err := currentContextNamespace(configFlags)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(streams.Out, "namespace of current context is: %s\n", namespace)
+
+	discoveryclient, err := newDiscoveryClient(configFlags, discoveryCacheTTL)
+	if err != nil {
+		return fmt.Errorf("cannot create discovery client: %w", err)
+	}
+
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot create rest config: %w", err)
+	}
+
+	dynamicClient := dynamic.NewForConfigOrDie(restConfig)
+
+	filter := newResourceFilter(e.Include, e.Exclude, e.ClusterScope)
+
+	extractor := NewExtractor(discoveryclient, dynamicClient, namespace, e.MaxInflight, filter, e.Selector, e.FieldSelector, streams.ErrOut)
+
+	results, runErr := extractor.Run(context.Background())
+	if runErr != nil {
+		fmt.Fprintf(streams.ErrOut, "warning: %v\n", runErr)
+	}
+
+	resources := make([]groupResource, 0, len(results))
+	for _, r := range results {
+		resources = append(resources, r.GroupResource)
+	}
+
+	sort.Stable(sortableResource{resources, "kind"})
+
+	fmt.Fprintf(streams.Out, "\nGVK's to be backed up\n\n")
+
+	for _, r := range resources {
+		fmt.Fprintf(streams.Out, "%s\n", r.APIResource.Name+r.APIGroupVersion)
+	}
+
+	if e.OutputDir == "" {
+		return runErr
+	}
+
+	grByUID := make(map[string]export.GroupResource)
+	var objects []unstructured.Unstructured
+	for _, r := range results {
+		gr := export.GroupResource{
+			Group:    r.GroupResource.APIGroup,
+			Version:  r.GroupResource.APIVersion,
+			Resource: r.GroupResource.APIResource.Name,
+			Kind:     r.GroupResource.APIResource.Kind,
+		}
+		for _, obj := range r.Objects.Items {
+			grByUID[string(obj.GetUID())] = gr
+			objects = append(objects, obj)
+		}
+	}
+
+	pruned := prune.Prune(objects, e.KeepOwned)
+
+	items := make([]export.Item, 0, len(pruned))
+	for _, obj := range pruned {
+		items = append(items, export.Item{
+			GroupResource: grByUID[string(obj.GetUID())],
+			Object:        obj,
+		})
+	}
+
+	exporter := export.New(export.Options{
+		OutputDir:   e.OutputDir,
+		Format:      export.Format(e.Format),
+		StripStatus: e.StripStatus,
+		Kustomize:   e.Kustomize,
+	})
+
+	if err := exporter.Export(items); err != nil {
+		return fmt.Errorf("error exporting objects: %w", err)
+	}
+
+	return runErr
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/alaypatel07/extractor/pkg/lookup"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+type getOptions struct {
+	APIVersion string
+	Namespace  string
+	Selector   string
+}
+
+// newGetCommand builds "extract get <kind> [name]", which resolves kind
+// (or resource name) via a cached RESTMapper and prints the matching
+// object(s) as YAML.
+func newGetCommand(streams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, discoveryCacheTTL *time.Duration) *cobra.Command {
+	o := &getOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "get <kind> [name]",
+		Short: "Resolve a kind or resource name and fetch the matching object(s)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) == 2 {
+				name = args[1]
+			}
+			return runGet(streams, configFlags, *discoveryCacheTTL, o, args[0], name)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&o.APIVersion, "api-version", o.APIVersion, "apiVersion to disambiguate the kind/resource, e.g. \"apps/v1\"")
+	flags.StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "namespace to fetch from; defaults to the current context's namespace")
+	flags.StringVarP(&o.Selector, "selector", "l", o.Selector, "label selector, only used when no name is given")
+
+	return cmd
+}
+
+func runGet(streams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, discoveryCacheTTL time.Duration, o *getOptions, kindOrResource, name string) error {
+	namespace := o.Namespace
+	if namespace == "" {
+		ns, err := currentContextNamespace(configFlags)
+		if err != nil {
+			return err
+		}
+		namespace = ns
+	}
+
+	discoveryClient, err := newDiscoveryClient(configFlags, discoveryCacheTTL)
+	if err != nil {
+		return fmt.Errorf("cannot create discovery client: %w", err)
+	}
+
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot create rest config: %w", err)
+	}
+	dynamicClient := dynamic.NewForConfigOrDie(restConfig)
+
+	l, err := lookup.New(discoveryClient, dynamicClient)
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		obj, err := l.Get(context.Background(), o.APIVersion, kindOrResource, namespace, name)
+		if err != nil {
+			return fmt.Errorf("getting %s %q: %w", kindOrResource, name, err)
+		}
+		return printObject(streams, obj)
+	}
+
+	list, err := l.List(context.Background(), o.APIVersion, kindOrResource, namespace, metav1.ListOptions{LabelSelector: o.Selector})
+	if err != nil {
+		return fmt.Errorf("listing %s: %w", kindOrResource, err)
+	}
+	for i := range list.Items {
+		if err := printObject(streams, &list.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printObject(streams genericclioptions.IOStreams, obj *unstructured.Unstructured) error {
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("marshaling %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	fmt.Fprintf(streams.Out, "---\n%s", data)
+	return nil
+}
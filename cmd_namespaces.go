@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/alaypatel07/extractor/pkg/lookup"
+	"github.com/spf13/cobra"
+)
+
+// newNamespacesCommand builds "extract namespaces", which lists every
+// namespace in the cluster via the same RESTMapper-backed lookup helper
+// used by "extract get".
+func newNamespacesCommand(streams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, discoveryCacheTTL *time.Duration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "namespaces",
+		Short: "List every namespace in the cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNamespaces(streams, configFlags, *discoveryCacheTTL)
+		},
+	}
+}
+
+func runNamespaces(streams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, discoveryCacheTTL time.Duration) error {
+	discoveryClient, err := newDiscoveryClient(configFlags, discoveryCacheTTL)
+	if err != nil {
+		return fmt.Errorf("cannot create discovery client: %w", err)
+	}
+
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot create rest config: %w", err)
+	}
+	dynamicClient := dynamic.NewForConfigOrDie(restConfig)
+
+	l, err := lookup.New(discoveryClient, dynamicClient)
+	if err != nil {
+		return err
+	}
+
+	list, err := l.List(context.Background(), "v1", "namespaces", "", metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing namespaces: %w", err)
+	}
+
+	for _, ns := range list.Items {
+		fmt.Fprintln(streams.Out, ns.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// defaultGVRTimeout bounds how long a single GVR's List call is allowed to
+// take, so a hung or slow resource can't stall the rest of the scan.
+const defaultGVRTimeout = 30 * time.Second
+
+// defaultDiscoveryRetries matches the retry count upstream "get everything"
+// tools use around ServerPreferredResources.
+const defaultDiscoveryRetries = 2
+
+// Extractor walks the server's preferred resources and lists the objects for
+// each one, running the List calls concurrently with at most maxInflight of
+// them in flight at once.
+type Extractor struct {
+	discoveryClient discovery.DiscoveryInterface
+	dynamicClient   dynamic.Interface
+
+	namespace   string
+	maxInflight int
+
+	filter        *resourceFilter
+	selector      string
+	fieldSelector string
+
+	ErrOut io.Writer
+}
+
+// NewExtractor returns an Extractor ready to Run.
+func NewExtractor(discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, namespace string, maxInflight int, filter *resourceFilter, selector, fieldSelector string, errOut io.Writer) *Extractor {
+	if maxInflight <= 0 {
+		maxInflight = 1
+	}
+	if filter == nil {
+		filter = newResourceFilter(nil, nil, false)
+	}
+	return &Extractor{
+		discoveryClient: discoveryClient,
+		dynamicClient:   dynamicClient,
+		namespace:       namespace,
+		maxInflight:     maxInflight,
+		filter:          filter,
+		selector:        selector,
+		fieldSelector:   fieldSelector,
+		ErrOut:          errOut,
+	}
+}
+
+// ExtractionResult pairs a discovered groupResource with the objects Run
+// found for it in the target namespace.
+type ExtractionResult struct {
+	GroupResource groupResource
+	Objects       *unstructured.UnstructuredList
+}
+
+// Run discovers every preferred resource and lists objects for each one,
+// returning an ExtractionResult for every groupResource that has at least
+// one object matching the configured filters and selectors. Each
+// groupResource is listed in its own goroutine, with at most e.maxInflight
+// List calls in flight at once, each call bounded by defaultGVRTimeout. A single GVR's listing error (including a timeout) is
+// collected and reported, not treated as fatal: it neither cancels sibling
+// goroutines nor discards results already gathered from them, so one hung
+// or erroring resource can't stall or blank out the whole scan. Run only
+// returns a non-nil error once every goroutine has finished, aggregating
+// whatever hard errors occurred alongside the partial results.
+func (e *Extractor) Run(ctx context.Context) ([]ExtractionResult, error) {
+	lists, err := serverPreferredResourcesWithRetry(e.discoveryClient, e.ErrOut)
+	if err != nil {
+		fmt.Fprintf(e.ErrOut, "error discovering server preferred resources: %v\n", err)
+	}
+
+	candidates := groupResourcesFromLists(lists, e.filter, e.ErrOut)
+
+	sem := make(chan struct{}, e.maxInflight)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var results []ExtractionResult
+	var errs []error
+
+	for _, gr := range candidates {
+		gr := gr
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			reqCtx, cancel := context.WithTimeout(ctx, defaultGVRTimeout)
+			defer cancel()
+
+			objs, err := getObjects(reqCtx, gr, e.namespace, e.selector, e.fieldSelector, e.dynamicClient)
+			if err != nil {
+				switch {
+				case apierrors.IsForbidden(err):
+					fmt.Fprintf(e.ErrOut, "cannot list %s in namespace\n", gr.APIGroupVersion)
+				case apierrors.IsMethodNotSupported(err):
+					fmt.Fprintf(e.ErrOut, "list method not supported on %s\n", gr.APIGroupVersion)
+				case apierrors.IsNotFound(err):
+					fmt.Fprintf(e.ErrOut, "could not find %s, most likely this is a virtual resource\n", gr.APIGroupVersion)
+				default:
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("error listing %s: %w", gr.APIGroupVersion, err))
+					mu.Unlock()
+				}
+				return
+			}
+
+			if len(objs.Items) > 0 {
+				mu.Lock()
+				results = append(results, ExtractionResult{GroupResource: gr, Objects: objs})
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, fmt.Errorf("%d of %d resources failed to list: %w", len(errs), len(candidates), combineErrors(errs))
+	}
+
+	return results, nil
+}
+
+// combineErrors joins errs into a single error for reporting, preserving
+// each one's message. It always returns a non-nil error when errs is
+// non-empty.
+func combineErrors(errs []error) error {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// serverPreferredResourcesWithRetry calls ServerPreferredResources, retrying
+// up to defaultDiscoveryRetries times on transient errors. A
+// discovery.ErrGroupDiscoveryFailed (a stale or unreachable aggregated
+// APIService, a common failure mode) is not retried: its partial resource
+// list is accepted and the groups that failed are surfaced as warnings
+// instead of aborting the whole scan.
+func serverPreferredResourcesWithRetry(d discovery.DiscoveryInterface, errOut io.Writer) ([]*metav1.APIResourceList, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= defaultDiscoveryRetries; attempt++ {
+		lists, err := d.ServerPreferredResources()
+		if err == nil {
+			return lists, nil
+		}
+
+		var groupErr *discovery.ErrGroupDiscoveryFailed
+		if errors.As(err, &groupErr) {
+			for gv, gerr := range groupErr.Groups {
+				fmt.Fprintf(errOut, "warning: could not discover %s, continuing without it: %v\n", gv, gerr)
+			}
+			return lists, nil
+		}
+
+		lastErr = err
+		if attempt < defaultDiscoveryRetries {
+			fmt.Fprintf(errOut, "discovery failed, retrying (%d/%d): %v\n", attempt+1, defaultDiscoveryRetries, err)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// groupResourcesFromLists flattens the discovery client's preferred
+// resources into the groupResources worth listing, according to filter.
+func groupResourcesFromLists(lists []*metav1.APIResourceList, filter *resourceFilter, errOut io.Writer) []groupResource {
+	var candidates []groupResource
+
+	for _, list := range lists {
+		if len(list.APIResources) == 0 {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if len(resource.Verbs) == 0 {
+				continue
+			}
+
+			if !filter.allows(resource) {
+				if !resource.Namespaced {
+					fmt.Fprintf(errOut, "resource: %s.%s is clusterscoped, skipping\n", gv.String(), resource.Kind)
+				}
+				continue
+			}
+
+			candidates = append(candidates, groupResource{
+				APIGroup:        gv.Group,
+				APIVersion:      gv.Version,
+				APIGroupVersion: gv.String(),
+				APIResource:     resource,
+			})
+		}
+	}
+
+	return candidates
+}
+
+func getObjects(ctx context.Context, g groupResource, namespace, selector, fieldSelector string, d dynamic.Interface) (*unstructured.UnstructuredList, error) {
+	c := d.Resource(schema.GroupVersionResource{
+		Group:    g.APIGroup,
+		Version:  g.APIVersion,
+		Resource: g.APIResource.Name,
+	})
+
+	listOptions := metav1.ListOptions{
+		LabelSelector: selector,
+		FieldSelector: fieldSelector,
+	}
+
+	if !g.APIResource.Namespaced {
+		return c.List(ctx, listOptions)
+	}
+	return c.Namespace(namespace).List(ctx, listOptions)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// fakeDiscovery overrides only ServerPreferredResources, embedding a nil
+// discovery.DiscoveryInterface so the other methods are never exercised by
+// Run.
+type fakeDiscovery struct {
+	discovery.DiscoveryInterface
+	lists []*metav1.APIResourceList
+}
+
+func (f *fakeDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return f.lists, nil
+}
+
+// TestRunPartialFailure verifies that one GVR hitting a hard listing error
+// (e.g. the defaultGVRTimeout deadline expiring) doesn't cancel sibling
+// goroutines or discard results they've already collected: Run must still
+// return every other GVR's objects, aggregating the failure into its error
+// instead of returning a nil result set.
+func TestRunPartialFailure(t *testing.T) {
+	scheme := runtime.NewScheme()
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		{Group: "", Version: "v1", Resource: "pods"}:       "PodList",
+		{Group: "", Version: "v1", Resource: "services"}:   "ServiceList",
+		{Group: "", Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+	}
+
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1", "kind": "Pod",
+		"metadata": map[string]interface{}{"name": "pod-1", "namespace": "ns"},
+	}}
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1", "kind": "ConfigMap",
+		"metadata": map[string]interface{}{"name": "cm-1", "namespace": "ns"},
+	}}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind, pod, cm)
+
+	wantErr := errors.New("dial tcp: i/o timeout")
+	dynamicClient.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, wantErr
+	})
+
+	disco := &fakeDiscovery{lists: []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"list"}},
+				{Name: "services", Kind: "Service", Namespaced: true, Verbs: metav1.Verbs{"list"}},
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"list"}},
+			},
+		},
+	}}
+
+	e := NewExtractor(disco, dynamicClient, "ns", 3, nil, "", "", &discardWriter{})
+
+	results, err := e.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected Run to return an aggregated error for the failed GVR")
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected results for the 2 succeeding GVRs to survive the 3rd's failure, got %d: %+v", len(results), results)
+	}
+
+	var gotResources []string
+	for _, r := range results {
+		gotResources = append(gotResources, r.GroupResource.APIResource.Name)
+	}
+	for _, want := range []string{"pods", "configmaps"} {
+		found := false
+		for _, got := range gotResources {
+			if got == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected a result for %q, got %v", want, gotResources)
+		}
+	}
+}
+
+type discardWriter struct{}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
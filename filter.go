@@ -0,0 +1,61 @@
+package main
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resourceFilter decides which discovered resources an Extractor run should
+// consider, on top of the namespaced/cluster-scoped split.
+type resourceFilter struct {
+	clusterScope bool
+	include      map[string]struct{}
+	exclude      map[string]struct{}
+}
+
+// newResourceFilter builds a resourceFilter from the --include/--exclude
+// flag values. Entries are matched against both a resource's plural name
+// (e.g. "pods") and its short names (e.g. "po").
+func newResourceFilter(include, exclude []string, clusterScope bool) *resourceFilter {
+	return &resourceFilter{
+		clusterScope: clusterScope,
+		include:      toSet(include),
+		exclude:      toSet(exclude),
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// allows reports whether resource should be considered for this run.
+func (f *resourceFilter) allows(resource metav1.APIResource) bool {
+	if !resource.Namespaced && !f.clusterScope {
+		return false
+	}
+
+	if len(f.include) > 0 && !f.matches(resource, f.include) {
+		return false
+	}
+
+	if f.matches(resource, f.exclude) {
+		return false
+	}
+
+	return true
+}
+
+func (f *resourceFilter) matches(resource metav1.APIResource, set map[string]struct{}) bool {
+	if _, ok := set[resource.Name]; ok {
+		return true
+	}
+	for _, short := range resource.ShortNames {
+		if _, ok := set[short]; ok {
+			return true
+		}
+	}
+	return false
+}
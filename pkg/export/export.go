@@ -0,0 +1,247 @@
+// Package export writes discovered Kubernetes objects to disk as
+// re-appliable manifests.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Format selects how manifests are written to disk.
+type Format string
+
+const (
+	// FormatYAML writes one YAML file per object.
+	FormatYAML Format = "yaml"
+	// FormatJSON writes one JSON file per object.
+	FormatJSON Format = "json"
+	// FormatSingleFile concatenates every object into a single
+	// YAML file, separated by "---" documents.
+	FormatSingleFile Format = "single-file"
+)
+
+// GroupResource is the subset of discovery information the Exporter needs
+// to lay objects out on disk.
+type GroupResource struct {
+	Group    string
+	Version  string
+	Resource string
+	Kind     string
+}
+
+// GroupVersion returns the "group/version" (or just "version" for the core
+// group) string for gr.
+func (gr GroupResource) GroupVersion() string {
+	if gr.Group == "" {
+		return gr.Version
+	}
+	return gr.Group + "/" + gr.Version
+}
+
+// Item bundles a discovered resource with one object found for it. Items
+// are written in the order given, so callers that care about apply order
+// (see pkg/prune) should sort before calling Export.
+type Item struct {
+	GroupResource GroupResource
+	Object        unstructured.Unstructured
+}
+
+// Options configures an Exporter.
+type Options struct {
+	// OutputDir is the directory objects are written under. It is
+	// created if it does not already exist.
+	OutputDir string
+	// Format is one of FormatYAML, FormatJSON, or FormatSingleFile.
+	Format Format
+	// StripStatus additionally removes the status subresource from
+	// every object before writing it.
+	StripStatus bool
+	// Kustomize, when set, writes a kustomization.yaml listing every
+	// exported file alongside them.
+	Kustomize bool
+}
+
+// Exporter writes collected objects to OutputDir, stripping the
+// server-populated fields that would otherwise prevent a clean re-apply.
+type Exporter struct {
+	opts Options
+}
+
+// New returns an Exporter configured by opts.
+func New(opts Options) *Exporter {
+	if opts.Format == "" {
+		opts.Format = FormatYAML
+	}
+	return &Exporter{opts: opts}
+}
+
+// serverPopulatedMetadataFields are stripped from every exported object so
+// the output can be re-applied to a cluster without conflicting with
+// server-assigned state.
+var serverPopulatedMetadataFields = []string{
+	"resourceVersion",
+	"uid",
+	"creationTimestamp",
+	"managedFields",
+	"selfLink",
+	"generation",
+}
+
+// Export writes every object in items to e.opts.OutputDir.
+func (e *Exporter) Export(items []Item) error {
+	if err := os.MkdirAll(e.opts.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("creating output dir %q: %w", e.opts.OutputDir, err)
+	}
+
+	var files []string
+
+	if e.opts.Format == FormatSingleFile {
+		f, err := e.writeSingleFile(items)
+		if err != nil {
+			return err
+		}
+		if f != "" {
+			files = append(files, f)
+		}
+	} else {
+		for _, item := range items {
+			obj := item.Object.DeepCopy()
+			e.strip(obj)
+
+			path, err := e.writeObject(item.GroupResource, obj)
+			if err != nil {
+				return err
+			}
+			files = append(files, path)
+		}
+	}
+
+	if e.opts.Kustomize {
+		if err := e.writeKustomization(files); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) strip(obj *unstructured.Unstructured) {
+	for _, field := range serverPopulatedMetadataFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	if e.opts.StripStatus {
+		unstructured.RemoveNestedField(obj.Object, "status")
+	}
+}
+
+func (e *Exporter) writeObject(gr GroupResource, obj *unstructured.Unstructured) (string, error) {
+	dir := filepath.Join(e.opts.OutputDir, fmt.Sprintf("%s.%s", gr.Resource, strings.ReplaceAll(gr.GroupVersion(), "/", "_")))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating dir %q: %w", dir, err)
+	}
+
+	name := obj.GetName()
+	if ns := obj.GetNamespace(); ns != "" {
+		name = ns + "-" + name
+	}
+
+	ext := "yaml"
+	if e.opts.Format == FormatJSON {
+		ext = "json"
+	}
+	path := filepath.Join(dir, name+"."+ext)
+
+	data, err := e.marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("marshaling %s %s: %w", gr.Kind, obj.GetName(), err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing %q: %w", path, err)
+	}
+
+	return path, nil
+}
+
+func (e *Exporter) writeSingleFile(items []Item) (string, error) {
+	var docs [][]byte
+
+	for _, item := range items {
+		obj := item.Object.DeepCopy()
+		e.strip(obj)
+
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", fmt.Errorf("marshaling %s %s: %w", item.GroupResource.Kind, obj.GetName(), err)
+		}
+		docs = append(docs, data)
+	}
+
+	if len(docs) == 0 {
+		return "", nil
+	}
+
+	path := filepath.Join(e.opts.OutputDir, "manifests.yaml")
+	if err := os.WriteFile(path, []byte(strings.Join(bytesToStrings(docs), "---\n")), 0o644); err != nil {
+		return "", fmt.Errorf("writing %q: %w", path, err)
+	}
+
+	return path, nil
+}
+
+func (e *Exporter) marshal(obj map[string]interface{}) ([]byte, error) {
+	if e.opts.Format == FormatJSON {
+		return json.MarshalIndent(obj, "", "  ")
+	}
+	return yaml.Marshal(obj)
+}
+
+func bytesToStrings(docs [][]byte) []string {
+	out := make([]string, len(docs))
+	for i, d := range docs {
+		out[i] = string(d)
+	}
+	return out
+}
+
+// writeKustomization writes a kustomization.yaml at e.opts.OutputDir
+// listing every file in files (relative to OutputDir) as a resource, so
+// `kubectl apply -k` can round-trip the export.
+func (e *Exporter) writeKustomization(files []string) error {
+	resources := make([]string, 0, len(files))
+	for _, f := range files {
+		rel, err := filepath.Rel(e.opts.OutputDir, f)
+		if err != nil {
+			rel = f
+		}
+		resources = append(resources, rel)
+	}
+
+	kustomization := struct {
+		APIVersion string   `json:"apiVersion"`
+		Kind       string   `json:"kind"`
+		Resources  []string `json:"resources"`
+	}{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  resources,
+	}
+
+	data, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return fmt.Errorf("marshaling kustomization.yaml: %w", err)
+	}
+
+	path := filepath.Join(e.opts.OutputDir, "kustomization.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+
+	return nil
+}
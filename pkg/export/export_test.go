@@ -0,0 +1,110 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func pod(name, namespace string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         namespace,
+			"uid":               "some-uid",
+			"resourceVersion":   "123",
+			"creationTimestamp": "2020-01-01T00:00:00Z",
+		},
+		"status": map[string]interface{}{
+			"phase": "Running",
+		},
+	}}
+}
+
+func TestExportWritesOneYAMLFilePerObject(t *testing.T) {
+	dir := t.TempDir()
+	e := New(Options{OutputDir: dir})
+
+	gr := GroupResource{Version: "v1", Resource: "pods", Kind: "Pod"}
+	if err := e.Export([]Item{{GroupResource: gr, Object: pod("web-1", "ns-a")}}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	path := filepath.Join(dir, "pods.v1", "ns-a-web-1.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected %q to exist: %v", path, err)
+	}
+	if len(data) == 0 {
+		t.Errorf("expected non-empty manifest at %q", path)
+	}
+}
+
+func TestExportStripsServerPopulatedFields(t *testing.T) {
+	dir := t.TempDir()
+	e := New(Options{OutputDir: dir, StripStatus: true})
+
+	gr := GroupResource{Version: "v1", Resource: "pods", Kind: "Pod"}
+	if err := e.Export([]Item{{GroupResource: gr, Object: pod("web-1", "ns-a")}}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "pods.v1", "ns-a-web-1.yaml"))
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+
+	for _, want := range []string{"resourceVersion", "uid", "creationTimestamp", "status"} {
+		if strings.Contains(string(data), want) {
+			t.Errorf("expected %q to be stripped from exported manifest, got:\n%s", want, data)
+		}
+	}
+}
+
+func TestExportSingleFileConcatenatesDocuments(t *testing.T) {
+	dir := t.TempDir()
+	e := New(Options{OutputDir: dir, Format: FormatSingleFile})
+
+	gr := GroupResource{Version: "v1", Resource: "pods", Kind: "Pod"}
+	items := []Item{
+		{GroupResource: gr, Object: pod("web-1", "ns-a")},
+		{GroupResource: gr, Object: pod("web-2", "ns-a")},
+	}
+	if err := e.Export(items); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "manifests.yaml"))
+	if err != nil {
+		t.Fatalf("reading manifests.yaml: %v", err)
+	}
+	if !strings.Contains(string(data), "web-1") || !strings.Contains(string(data), "web-2") {
+		t.Errorf("expected both objects in single-file output, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "---") {
+		t.Errorf("expected \"---\" document separator, got:\n%s", data)
+	}
+}
+
+func TestExportKustomizeListsEveryFile(t *testing.T) {
+	dir := t.TempDir()
+	e := New(Options{OutputDir: dir, Kustomize: true})
+
+	gr := GroupResource{Version: "v1", Resource: "pods", Kind: "Pod"}
+	if err := e.Export([]Item{{GroupResource: gr, Object: pod("web-1", "ns-a")}}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+	if err != nil {
+		t.Fatalf("expected kustomization.yaml to exist: %v", err)
+	}
+	if !strings.Contains(string(data), "ns-a-web-1.yaml") {
+		t.Errorf("expected kustomization.yaml to list the exported file, got:\n%s", data)
+	}
+}
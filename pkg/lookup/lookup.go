@@ -0,0 +1,147 @@
+// Package lookup resolves a Kind or resource name to its
+// GroupVersionResource via a cached RESTMapper, and fetches objects for it
+// through a dynamic client.
+package lookup
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// Lookup resolves a Kind or resource name (e.g. "Pod", "pods", "po") to its
+// GroupVersionResource and fetches objects for it. The RESTMapper backing
+// Resolve is computed once in New, so repeated lookups don't re-walk
+// ServerPreferredResources; a miss invalidates the underlying discovery
+// cache and rebuilds the mapper once, so a resource added after the cache
+// was populated (e.g. a freshly installed CRD) is still found.
+type Lookup struct {
+	discoveryClient discovery.CachedDiscoveryInterface
+	dynamicClient   dynamic.Interface
+	mapper          meta.RESTMapper
+}
+
+// New builds a Lookup backed by a RESTMapper computed once from
+// discoveryClient's preferred resources, with short names (e.g. "po" for
+// "pods") expanded via the same discovery data.
+func New(discoveryClient discovery.CachedDiscoveryInterface, dynamicClient dynamic.Interface) (*Lookup, error) {
+	mapper, err := buildMapper(discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Lookup{
+		discoveryClient: discoveryClient,
+		dynamicClient:   dynamicClient,
+		mapper:          mapper,
+	}, nil
+}
+
+func buildMapper(discoveryClient discovery.DiscoveryInterface) (meta.RESTMapper, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return nil, fmt.Errorf("computing REST mappings: %w", err)
+	}
+	return restmapper.NewShortcutExpander(restmapper.NewDiscoveryRESTMapper(groupResources), discoveryClient), nil
+}
+
+// Resolve maps a Kind or resource name to a GroupVersionResource. apiVersion
+// may be empty (use the RESTMapper's preferred version) or "version" /
+// "group/version". A miss invalidates the discovery cache and retries once
+// against freshly discovered data before giving up.
+func (l *Lookup) Resolve(apiVersion, kindOrResource string) (schema.GroupVersionResource, error) {
+	mapping, err := l.resolveMapping(apiVersion, kindOrResource)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
+
+// resolveMapping is Resolve, but returns the full RESTMapping so callers can
+// also see the resource's scope (namespaced vs. cluster) without a second
+// RESTMapper walk.
+func (l *Lookup) resolveMapping(apiVersion, kindOrResource string) (*meta.RESTMapping, error) {
+	mapping, err := l.mapping(apiVersion, kindOrResource)
+	if err == nil {
+		return mapping, nil
+	}
+
+	l.discoveryClient.Invalidate()
+	mapper, buildErr := buildMapper(l.discoveryClient)
+	if buildErr != nil {
+		return nil, err
+	}
+	l.mapper = mapper
+
+	return l.mapping(apiVersion, kindOrResource)
+}
+
+func (l *Lookup) mapping(apiVersion, kindOrResource string) (*meta.RESTMapping, error) {
+	var gv schema.GroupVersion
+	if apiVersion != "" {
+		parsed, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+		}
+		gv = parsed
+	}
+
+	if gvr, err := l.mapper.ResourceFor(schema.GroupVersionResource{Group: gv.Group, Version: gv.Version, Resource: kindOrResource}); err == nil {
+		if gvk, err := l.mapper.KindFor(gvr); err == nil {
+			if mapping, err := l.mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+				return mapping, nil
+			}
+		}
+	}
+
+	var versions []string
+	if gv.Version != "" {
+		versions = []string{gv.Version}
+	}
+	mapping, err := l.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kindOrResource}, versions...)
+	if err != nil {
+		return nil, fmt.Errorf("no resource or kind %q found on the server: %w", kindOrResource, err)
+	}
+	return mapping, nil
+}
+
+// Get resolves apiVersion/resource and fetches the single named object.
+// namespace is ignored for cluster-scoped resources, the same way getObjects
+// in the main package branches on APIResource.Namespaced before calling
+// Namespace().
+func (l *Lookup) Get(ctx context.Context, apiVersion, resource, namespace, name string) (*unstructured.Unstructured, error) {
+	mapping, err := l.resolveMapping(apiVersion, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	client := l.dynamicClient.Resource(mapping.Resource)
+	if namespace == "" || mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return client.Get(ctx, name, metav1.GetOptions{})
+	}
+	return client.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// List resolves apiVersion/resource and lists every object of that kind.
+// namespace is ignored for cluster-scoped resources, the same way getObjects
+// in the main package branches on APIResource.Namespaced before calling
+// Namespace().
+func (l *Lookup) List(ctx context.Context, apiVersion, resource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	mapping, err := l.resolveMapping(apiVersion, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	client := l.dynamicClient.Resource(mapping.Resource)
+	if namespace == "" || mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return client.List(ctx, opts)
+	}
+	return client.Namespace(namespace).List(ctx, opts)
+}
@@ -0,0 +1,109 @@
+package lookup
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// fakeCachedDiscovery adapts discoveryfake.FakeDiscovery (which only
+// implements discovery.DiscoveryInterface) to discovery.CachedDiscoveryInterface
+// so it can back a Lookup in tests.
+type fakeCachedDiscovery struct {
+	*discoveryfake.FakeDiscovery
+}
+
+func (f *fakeCachedDiscovery) Fresh() bool { return true }
+func (f *fakeCachedDiscovery) Invalidate() {}
+
+func newTestLookup(t *testing.T, objects ...runtime.Object) *Lookup {
+	t.Helper()
+
+	discoveryClient := &fakeCachedDiscovery{&discoveryfake.FakeDiscovery{
+		Fake: &clienttesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{
+						{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+						{Name: "namespaces", Kind: "Namespace", Namespaced: false, Verbs: metav1.Verbs{"get", "list"}},
+					},
+				},
+			},
+		},
+	}}
+
+	scheme := runtime.NewScheme()
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		{Group: "", Version: "v1", Resource: "pods"}:       "PodList",
+		{Group: "", Version: "v1", Resource: "namespaces"}: "NamespaceList",
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind, objects...)
+
+	l, err := New(discoveryClient, dynamicClient)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return l
+}
+
+func TestGetClusterScopedIgnoresNamespace(t *testing.T) {
+	ns := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1", "kind": "Namespace",
+		"metadata": map[string]interface{}{"name": "prod"},
+	}}
+	l := newTestLookup(t, ns)
+
+	// A cluster-scoped kind must be reachable even when a (bogus) namespace
+	// is passed in, since namespaces never have one of their own.
+	got, err := l.Get(context.Background(), "v1", "Namespace", "some-namespace", "prod")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.GetName() != "prod" {
+		t.Errorf("got object %q, want %q", got.GetName(), "prod")
+	}
+}
+
+func TestListClusterScopedIgnoresNamespace(t *testing.T) {
+	ns := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1", "kind": "Namespace",
+		"metadata": map[string]interface{}{"name": "prod"},
+	}}
+	l := newTestLookup(t, ns)
+
+	list, err := l.List(context.Background(), "v1", "namespaces", "some-namespace", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(list.Items))
+	}
+}
+
+func TestGetNamespacedResourceUsesNamespace(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1", "kind": "Pod",
+		"metadata": map[string]interface{}{"name": "web-1", "namespace": "ns-a"},
+	}}
+	l := newTestLookup(t, pod)
+
+	if _, err := l.Get(context.Background(), "v1", "Pod", "ns-b", "web-1"); err == nil {
+		t.Fatal("expected a not-found error fetching a namespaced pod from the wrong namespace")
+	}
+
+	got, err := l.Get(context.Background(), "v1", "Pod", "ns-a", "web-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.GetName() != "web-1" {
+		t.Errorf("got object %q, want %q", got.GetName(), "web-1")
+	}
+}
@@ -0,0 +1,142 @@
+// Package prune removes owner-duplicated objects from a captured set and
+// orders what remains so a dependency-respecting apply doesn't have to
+// guess.
+package prune
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// kindPriority orders well-known scaffolding kinds ahead of generic
+// workloads: namespaces and CRDs need to exist before anything else can be
+// created in/of them, and config is typically consumed by workloads.
+var kindPriority = map[string]int{
+	"Namespace":                0,
+	"CustomResourceDefinition": 1,
+	"ConfigMap":                2,
+	"Secret":                   2,
+}
+
+// defaultPriority is used for any kind not listed in kindPriority.
+const defaultPriority = 3
+
+// Prune drops objects whose metadata.ownerReferences point at another
+// object already present in objects, unless keepOwned is set, then
+// topologically sorts the remainder so owners are ordered before the
+// objects that reference them. Ties are broken by kindPriority and then by
+// namespace/name, so Namespaces sort before CRDs, before ConfigMaps and
+// Secrets, before everything else.
+func Prune(objects []unstructured.Unstructured, keepOwned bool) []unstructured.Unstructured {
+	ownersByUID := make(map[string]struct{}, len(objects))
+	for _, o := range objects {
+		ownersByUID[string(o.GetUID())] = struct{}{}
+	}
+
+	kept := objects
+	if !keepOwned {
+		kept = make([]unstructured.Unstructured, 0, len(objects))
+		for _, o := range objects {
+			if !ownedWithinSet(o, ownersByUID) {
+				kept = append(kept, o)
+			}
+		}
+	}
+
+	return topoSort(kept)
+}
+
+func ownedWithinSet(o unstructured.Unstructured, ownersByUID map[string]struct{}) bool {
+	for _, ref := range o.GetOwnerReferences() {
+		if _, ok := ownersByUID[string(ref.UID)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// topoSort orders objects via Kahn's algorithm over the ownerReference
+// graph restricted to objects, breaking ties with lessPending so the
+// result is deterministic.
+func topoSort(objects []unstructured.Unstructured) []unstructured.Unstructured {
+	indexByUID := make(map[string]int, len(objects))
+	for i, o := range objects {
+		indexByUID[string(o.GetUID())] = i
+	}
+
+	indegree := make([]int, len(objects))
+	dependents := make([][]int, len(objects))
+
+	for i, o := range objects {
+		for _, ref := range o.GetOwnerReferences() {
+			if owner, ok := indexByUID[string(ref.UID)]; ok {
+				indegree[i]++
+				dependents[owner] = append(dependents[owner], i)
+			}
+		}
+	}
+
+	var pending []int
+	for i := range objects {
+		if indegree[i] == 0 {
+			pending = append(pending, i)
+		}
+	}
+
+	order := make([]int, 0, len(objects))
+	for len(pending) > 0 {
+		sort.SliceStable(pending, func(a, b int) bool {
+			return lessPending(objects[pending[a]], objects[pending[b]])
+		})
+
+		next := pending[0]
+		pending = pending[1:]
+		order = append(order, next)
+
+		for _, dep := range dependents[next] {
+			indegree[dep]--
+			if indegree[dep] == 0 {
+				pending = append(pending, dep)
+			}
+		}
+	}
+
+	// A cycle in ownerReferences would otherwise strand objects; append
+	// them in their original order rather than dropping them.
+	if len(order) < len(objects) {
+		seen := make(map[int]bool, len(order))
+		for _, i := range order {
+			seen[i] = true
+		}
+		for i := range objects {
+			if !seen[i] {
+				order = append(order, i)
+			}
+		}
+	}
+
+	result := make([]unstructured.Unstructured, len(order))
+	for k, i := range order {
+		result[k] = objects[i]
+	}
+	return result
+}
+
+func lessPending(a, b unstructured.Unstructured) bool {
+	pa, pb := priority(a), priority(b)
+	if pa != pb {
+		return pa < pb
+	}
+	if a.GetNamespace() != b.GetNamespace() {
+		return a.GetNamespace() < b.GetNamespace()
+	}
+	return a.GetName() < b.GetName()
+}
+
+func priority(o unstructured.Unstructured) int {
+	if p, ok := kindPriority[o.GetKind()]; ok {
+		return p
+	}
+	return defaultPriority
+}
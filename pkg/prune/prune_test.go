@@ -0,0 +1,116 @@
+package prune
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func obj(uid, kind, namespace, name string, owners ...metav1.OwnerReference) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"uid":       uid,
+			"name":      name,
+			"namespace": namespace,
+		},
+	}}
+	if len(owners) > 0 {
+		u.SetOwnerReferences(owners)
+	}
+	return u
+}
+
+func ownerRef(uid string) metav1.OwnerReference {
+	return metav1.OwnerReference{UID: types.UID(uid)}
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	out := make([]string, len(objects))
+	for i, o := range objects {
+		out[i] = o.GetName()
+	}
+	return out
+}
+
+func TestPruneDropsObjectOwnedWithinSet(t *testing.T) {
+	owner := obj("owner-uid", "Deployment", "ns", "app")
+	owned := obj("rs-uid", "ReplicaSet", "ns", "app-rs", ownerRef("owner-uid"))
+
+	got := Prune([]unstructured.Unstructured{owner, owned}, false)
+
+	if len(got) != 1 {
+		t.Fatalf("got %d objects, want 1: %v", len(got), names(got))
+	}
+	if got[0].GetName() != "app" {
+		t.Errorf("got %q, want the owner to survive", got[0].GetName())
+	}
+}
+
+func TestPruneKeepsObjectOwnedOutsideSet(t *testing.T) {
+	// The owner (UID "missing-uid") was never captured, so the owned object
+	// must not be dropped even though it has an ownerReference.
+	owned := obj("rs-uid", "ReplicaSet", "ns", "app-rs", ownerRef("missing-uid"))
+
+	got := Prune([]unstructured.Unstructured{owned}, false)
+
+	if len(got) != 1 {
+		t.Fatalf("got %d objects, want 1: %v", len(got), names(got))
+	}
+	if got[0].GetName() != "app-rs" {
+		t.Errorf("got %q, want %q kept", got[0].GetName(), "app-rs")
+	}
+}
+
+func TestPruneKeepOwnedDisablesPruning(t *testing.T) {
+	owner := obj("owner-uid", "Deployment", "ns", "app")
+	owned := obj("rs-uid", "ReplicaSet", "ns", "app-rs", ownerRef("owner-uid"))
+
+	got := Prune([]unstructured.Unstructured{owner, owned}, true)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d objects with keepOwned=true, want 2: %v", len(got), names(got))
+	}
+}
+
+func TestPruneOrdersByKindPriority(t *testing.T) {
+	cm := obj("cm-uid", "ConfigMap", "ns", "cfg")
+	ns := obj("ns-uid", "Namespace", "", "ns")
+	deploy := obj("deploy-uid", "Deployment", "ns", "app")
+
+	got := Prune([]unstructured.Unstructured{deploy, cm, ns}, false)
+
+	want := []string{"ns", "cfg", "app"}
+	if gotNames := names(got); !equal(gotNames, want) {
+		t.Errorf("got order %v, want %v", gotNames, want)
+	}
+}
+
+func TestPruneCycleFallsBackToOriginalOrder(t *testing.T) {
+	// a owns b, b owns a: topoSort can never drain this pair via Kahn's
+	// algorithm, so both must still appear, in their original order.
+	a := obj("a-uid", "Widget", "ns", "a", ownerRef("b-uid"))
+	b := obj("b-uid", "Widget", "ns", "b", ownerRef("a-uid"))
+
+	got := Prune([]unstructured.Unstructured{a, b}, true)
+
+	want := []string{"a", "b"}
+	if gotNames := names(got); !equal(gotNames, want) {
+		t.Errorf("got order %v, want %v (original order preserved for a cycle)", gotNames, want)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	diskcached "k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/homedir"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultDiscoveryCacheTTL matches the TTL kubectl itself uses for its
+// on-disk discovery cache.
+const defaultDiscoveryCacheTTL = 10 * time.Minute
+
+// NewRootCommand builds the "extract" command tree: "extract all" (scan
+// everything in a namespace), "extract get" (resolve and fetch a single
+// kind/resource), and "extract namespaces" (list namespaces), all sharing
+// the same kubeconfig/discovery flags via configFlags.
+func NewRootCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	configFlags := genericclioptions.NewConfigFlags(true)
+	discoveryCacheTTL := defaultDiscoveryCacheTTL
+
+	root := &cobra.Command{
+		Use:   "extract",
+		Short: "Extract objects out of a Kubernetes cluster",
+		// A failed run prints cobra's "Error: ..." line to stderr,
+		// without the usage text.
+		SilenceUsage: true,
+	}
+	configFlags.AddFlags(root.PersistentFlags())
+	root.PersistentFlags().DurationVar(&discoveryCacheTTL, "discovery-cache-ttl", discoveryCacheTTL, "how long to reuse the on-disk discovery cache before re-querying the full resource catalog")
+
+	root.AddCommand(
+		newAllCommand(streams, configFlags, &discoveryCacheTTL),
+		newGetCommand(streams, configFlags, &discoveryCacheTTL),
+		newNamespacesCommand(streams, configFlags, &discoveryCacheTTL),
+	)
+
+	return root
+}
+
+// currentContextNamespace returns the namespace of the kubeconfig's current
+// context, the way the original single-command tool did, for subcommands
+// that don't take an explicit --namespace.
+func currentContextNamespace(configFlags *genericclioptions.ConfigFlags) (string, error) {
+	rawConfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("generating raw config: %w", err)
+	}
+
+	contextName := rawConfig.CurrentContext
+	if contextName == "" {
+		return "", fmt.Errorf("current context is empty")
+	}
+
+	var currentContext *api.Context
+	for name, ctx := range rawConfig.Contexts {
+		if name == contextName {
+			currentContext = ctx
+		}
+	}
+
+	if currentContext == nil {
+		return "", fmt.Errorf("currentContext is nil")
+	}
+
+	if len(currentContext.Namespace) == 0 {
+		return "", fmt.Errorf("currentContext namespace is empty")
+	}
+
+	return currentContext.Namespace, nil
+}
+
+// newDiscoveryClient builds a discovery client backed by an on-disk cache
+// under ~/.kube/cache/discovery (or --cache-dir/discovery, if set), valid
+// for ttl, so repeat runs don't re-hit the API server for the full resource
+// catalog.
+func newDiscoveryClient(configFlags *genericclioptions.ConfigFlags, ttl time.Duration) (discovery.CachedDiscoveryInterface, error) {
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create rest config: %w", err)
+	}
+
+	cacheDir := filepath.Join(homedir.HomeDir(), ".kube", "cache")
+	if configFlags.CacheDir != nil && *configFlags.CacheDir != "" {
+		cacheDir = *configFlags.CacheDir
+	}
+
+	return diskcached.NewCachedDiscoveryClientForConfig(
+		restConfig,
+		discoveryCacheDirForHost(filepath.Join(cacheDir, "discovery"), restConfig.Host),
+		filepath.Join(cacheDir, "http"),
+		ttl,
+	)
+}
+
+var illegalDiscoveryCacheDirChars = regexp.MustCompile(`[^(\w/.)]`)
+
+// discoveryCacheDirForHost namespaces parentDir by host, since
+// CachedDiscoveryClient's cache directory must be unique per host to avoid
+// serving one cluster's discovery data for another.
+func discoveryCacheDirForHost(parentDir, host string) string {
+	schemelessHost := strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	safeHost := illegalDiscoveryCacheDirChars.ReplaceAllString(schemelessHost, "_")
+	return filepath.Join(parentDir, safeHost)
+}